@@ -0,0 +1,618 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"CodeCity/server/interpreter/data"
+)
+
+// This file contains code that creates the JSON object and its
+// parse/stringify methods, per ES5.1 §15.12.
+
+func (intrp *Interpreter) initBuiltinJSON() {
+	intrp.mkBuiltin("JSON", data.NewObject(nil, intrp.protos.ObjectProto))
+
+	for _, ni := range builtinJSONNativeImpls {
+		intrp.mkBuiltinFunc(ni.Tag)
+	}
+}
+
+var builtinJSONNativeImpls = []NativeImpl{
+	{"JSON.parse", builtinJSONꞏparse, 2},
+	{"JSON.stringify", builtinJSONꞏstringify, 3},
+}
+
+/********************************************************************/
+// JSON.parse
+
+func builtinJSONꞏparse(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	text := string(args[0].ToString())
+	p := &jsonParser{intrp: intrp, src: text}
+	v, nErr := p.parseValue()
+	if nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	p.skipWhitespace()
+	if p.pos != len(p.src) {
+		return intrp.syntaxError("Unexpected non-whitespace character after JSON value"), true
+	}
+	var reviver data.Value
+	if len(args) > 1 {
+		reviver = args[1]
+	}
+	if reviver == nil || reviver == (data.Undefined{}) {
+		return v, false
+	}
+	holder := data.NewObject(nil, intrp.protos.ObjectProto)
+	if nErr := holder.Set("", v); nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	ret, nErr = jsonWalk(intrp, holder, "", reviver)
+	if nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	return ret, false
+}
+
+// jsonWalk implements the §15.12.2 Walk(holder, name) abstract
+// operation used to apply a reviver function to a freshly-parsed
+// JSON value tree.
+func jsonWalk(intrp *Interpreter, holder data.Object, name string, reviver data.Value) (data.Value, *data.ErrorMsg) {
+	val, nErr := holder.GetProperty(name)
+	if nErr != nil {
+		return nil, nErr
+	}
+	if obj, ok := val.(data.Object); ok {
+		if arr, ok := obj.(data.Array); ok {
+			len := int(arr.Length())
+			for i := 0; i < len; i++ {
+				key := strconv.Itoa(i)
+				elem, nErr := jsonWalk(intrp, obj, key, reviver)
+				if nErr != nil {
+					return nil, nErr
+				}
+				if elem == (data.Undefined{}) {
+					nErr = obj.DeleteProperty(key, false)
+				} else {
+					nErr = obj.Set(key, elem)
+				}
+				if nErr != nil {
+					return nil, nErr
+				}
+			}
+		} else {
+			for _, key := range obj.OwnPropertyKeys() {
+				pd, ok := obj.GetOwnProperty(key)
+				if !ok || !pd.IsEnumerable() {
+					continue
+				}
+				elem, nErr := jsonWalk(intrp, obj, key, reviver)
+				if nErr != nil {
+					return nil, nErr
+				}
+				if elem == (data.Undefined{}) {
+					nErr = obj.DeleteProperty(key, false)
+				} else {
+					nErr = obj.Set(key, elem)
+				}
+				if nErr != nil {
+					return nil, nErr
+				}
+			}
+		}
+	}
+	return intrp.call(reviver, holder, []data.Value{data.String(name), val})
+}
+
+// jsonParser is a recursive-descent parser for the strict JSON
+// grammar of §15.12.1, which (unlike object.NewFromRaw) rejects
+// trailing commas, single-quoted strings, and hex/octal/binary
+// numeric literals.
+type jsonParser struct {
+	intrp *Interpreter
+	src   string
+	pos   int
+}
+
+func (p *jsonParser) skipWhitespace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonParser) syntaxErrorf(format string, a ...interface{}) *data.ErrorMsg {
+	return &data.ErrorMsg{Name: "SyntaxError", Message: fmt.Sprintf(format, a...)}
+}
+
+func (p *jsonParser) parseValue() (data.Value, *data.ErrorMsg) {
+	p.skipWhitespace()
+	if p.pos >= len(p.src) {
+		return nil, p.syntaxErrorf("Unexpected end of JSON input")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return data.String(s), nil
+	case c == 't':
+		return p.parseLiteral("true", data.Boolean(true))
+	case c == 'f':
+		return p.parseLiteral("false", data.Boolean(false))
+	case c == 'n':
+		return p.parseLiteral("null", data.Null{})
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	default:
+		return nil, p.syntaxErrorf("Unexpected token %c in JSON at position %d", c, p.pos)
+	}
+}
+
+func (p *jsonParser) parseLiteral(lit string, v data.Value) (data.Value, *data.ErrorMsg) {
+	if !strings.HasPrefix(p.src[p.pos:], lit) {
+		return nil, p.syntaxErrorf("Unexpected token in JSON at position %d", p.pos)
+	}
+	p.pos += len(lit)
+	return v, nil
+}
+
+func (p *jsonParser) parseObject() (data.Value, *data.ErrorMsg) {
+	obj := data.NewObject(nil, p.intrp.protos.ObjectProto)
+	p.pos++ // consume '{'
+	p.skipWhitespace()
+	if p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		return obj, nil
+	}
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+			return nil, p.syntaxErrorf("Expected property name in JSON at position %d", p.pos)
+		}
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return nil, p.syntaxErrorf("Expected ':' in JSON at position %d", p.pos)
+		}
+		p.pos++ // consume ':'
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := obj.Set(key, val); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if p.pos >= len(p.src) {
+			return nil, p.syntaxErrorf("Unexpected end of JSON input")
+		}
+		if p.src[p.pos] == ',' {
+			p.pos++
+			continue // No trailing comma allowed: loop requires another member.
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			return obj, nil
+		}
+		return nil, p.syntaxErrorf("Expected ',' or '}' in JSON at position %d", p.pos)
+	}
+}
+
+func (p *jsonParser) parseArray() (data.Value, *data.ErrorMsg) {
+	arr := data.NewArray(nil, p.intrp.protos.ArrayProto)
+	p.pos++ // consume '['
+	p.skipWhitespace()
+	if p.pos < len(p.src) && p.src[p.pos] == ']' {
+		p.pos++
+		return arr, nil
+	}
+	i := 0
+	for {
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := arr.Set(strconv.Itoa(i), val); err != nil {
+			return nil, err
+		}
+		i++
+		p.skipWhitespace()
+		if p.pos >= len(p.src) {
+			return nil, p.syntaxErrorf("Unexpected end of JSON input")
+		}
+		if p.src[p.pos] == ',' {
+			p.pos++
+			continue // No trailing comma allowed: loop requires another element.
+		}
+		if p.src[p.pos] == ']' {
+			p.pos++
+			return arr, nil
+		}
+		return nil, p.syntaxErrorf("Expected ',' or ']' in JSON at position %d", p.pos)
+	}
+}
+
+func (p *jsonParser) parseString() (string, *data.ErrorMsg) {
+	p.pos++ // consume opening '"'
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			return "", p.syntaxErrorf("Unterminated string in JSON")
+		}
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		case c == '\\':
+			p.pos++
+			if p.pos >= len(p.src) {
+				return "", p.syntaxErrorf("Unterminated string escape in JSON")
+			}
+			switch p.src[p.pos] {
+			case '"':
+				sb.WriteByte('"')
+			case '\\':
+				sb.WriteByte('\\')
+			case '/':
+				sb.WriteByte('/')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if p.pos+4 >= len(p.src) {
+					return "", p.syntaxErrorf("Invalid unicode escape in JSON")
+				}
+				n, err := strconv.ParseUint(p.src[p.pos+1:p.pos+5], 16, 32)
+				if err != nil {
+					return "", p.syntaxErrorf("Invalid unicode escape in JSON")
+				}
+				sb.WriteRune(rune(n))
+				p.pos += 4
+			default:
+				return "", p.syntaxErrorf("Invalid escape character in JSON")
+			}
+			p.pos++
+		case c < 0x20:
+			return "", p.syntaxErrorf("Unescaped control character in JSON string")
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *jsonParser) parseNumber() (data.Value, *data.ErrorMsg) {
+	start := p.pos
+	if p.pos < len(p.src) && p.src[p.pos] == '-' {
+		p.pos++
+	}
+	intStart := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	// JSON requires an integer part and forbids leading zeroes (and so
+	// legacy octal literals like 013).
+	if p.pos == intStart || (p.src[intStart] == '0' && p.pos-intStart > 1) {
+		return nil, p.syntaxErrorf("Invalid number in JSON at position %d", start)
+	}
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		fracStart := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		if p.pos == fracStart {
+			return nil, p.syntaxErrorf("Invalid number in JSON at position %d", start)
+		}
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+	}
+	f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return nil, p.syntaxErrorf("Invalid number in JSON at position %d", start)
+	}
+	return data.Number(f), nil
+}
+
+/********************************************************************/
+// JSON.stringify
+
+func builtinJSONꞏstringify(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	var replacer, space data.Value
+	if len(args) > 1 {
+		replacer = args[1]
+	}
+	if len(args) > 2 {
+		space = args[2]
+	}
+	s := &jsonStringifier{intrp: intrp, stack: make(map[data.Object]bool)}
+
+	if fn, ok := replacer.(data.Object); ok {
+		if arr, ok := fn.(data.Array); ok {
+			s.propList = make([]string, 0, int(arr.Length()))
+			for i := 0; i < int(arr.Length()); i++ {
+				v, nErr := arr.GetProperty(strconv.Itoa(i))
+				if nErr != nil {
+					return intrp.nativeError(nErr), true
+				}
+				if str, ok := v.(data.String); ok {
+					s.propList = append(s.propList, string(str))
+				}
+			}
+		} else {
+			s.replacerFunc = fn
+		}
+	}
+
+	switch sp := space.(type) {
+	case data.Number:
+		n := int(sp)
+		if n > 10 {
+			n = 10
+		}
+		if n > 0 {
+			s.gap = strings.Repeat(" ", n)
+		}
+	case data.String:
+		str := string(sp)
+		if len(str) > 10 {
+			str = str[:10]
+		}
+		s.gap = str
+	}
+
+	holder := data.NewObject(nil, intrp.protos.ObjectProto)
+	if nErr := holder.Set("", args[0]); nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	str, nErr := s.str("", holder)
+	if nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	if str == nil {
+		return data.Undefined{}, false
+	}
+	return data.String(*str), false
+}
+
+// jsonStringifier holds the state (replacer, gap/indent and cycle
+// stack) threaded through the recursive §15.12.3 Str/JA/JO
+// operations for a single JSON.stringify call.
+type jsonStringifier struct {
+	intrp        *Interpreter
+	replacerFunc data.Value
+	propList     []string
+	gap          string
+	indent       string
+	stack        map[data.Object]bool
+}
+
+// str implements the §15.12.3 Str(key, holder) operation, returning
+// nil (no string) for values that JSON.stringify must omit
+// (undefined, functions, and symbols -- the latter not applicable
+// here).
+func (s *jsonStringifier) str(key string, holder data.Object) (*string, *data.ErrorMsg) {
+	value, nErr := holder.GetProperty(key)
+	if nErr != nil {
+		return nil, nErr
+	}
+	if obj, ok := value.(data.Object); ok {
+		toJSON, nErr := obj.GetProperty("toJSON")
+		if nErr != nil {
+			return nil, nErr
+		}
+		if s.intrp.isCallable(toJSON) {
+			value, nErr = s.intrp.call(toJSON, obj, []data.Value{data.String(key)})
+			if nErr != nil {
+				return nil, nErr
+			}
+		}
+	}
+	if s.replacerFunc != nil {
+		value, nErr = s.intrp.call(s.replacerFunc, holder, []data.Value{data.String(key), value})
+		if nErr != nil {
+			return nil, nErr
+		}
+	}
+	switch v := value.(type) {
+	case data.Null:
+		str := "null"
+		return &str, nil
+	case data.Boolean:
+		str := string(v.ToString())
+		return &str, nil
+	case data.String:
+		str := quoteJSONString(string(v))
+		return &str, nil
+	case data.Number:
+		f := float64(v)
+		var str string
+		if f != f || f > 1.7976931348623157e+308 || f < -1.7976931348623157e+308 {
+			str = "null" // NaN and ±Infinity stringify as null.
+		} else {
+			str = string(v.ToString())
+		}
+		return &str, nil
+	case data.Object:
+		if s.intrp.isCallable(v) {
+			return nil, nil
+		}
+		if arr, ok := v.(data.Array); ok {
+			str, nErr := s.ja(arr)
+			return &str, nErr
+		}
+		str, nErr := s.jo(v)
+		return &str, nErr
+	default:
+		return nil, nil
+	}
+}
+
+// ja implements §15.12.3's JA(value): serialize an array.
+func (s *jsonStringifier) ja(arr data.Array) (string, *data.ErrorMsg) {
+	obj := arr.(data.Object)
+	if s.stack[obj] {
+		return "", &data.ErrorMsg{Name: "TypeError", Message: "Converting circular structure to JSON"}
+	}
+	s.stack[obj] = true
+	defer delete(s.stack, obj)
+	stepback := s.indent
+	s.indent += s.gap
+
+	length := int(arr.Length())
+	parts := make([]string, length)
+	for i := 0; i < length; i++ {
+		elem, nErr := s.str(strconv.Itoa(i), obj)
+		if nErr != nil {
+			return "", nErr
+		}
+		if elem == nil {
+			parts[i] = "null"
+		} else {
+			parts[i] = *elem
+		}
+	}
+	result := s.join(parts, "[", "]")
+	s.indent = stepback
+	return result, nil
+}
+
+// jo implements §15.12.3's JO(value): serialize a plain object.
+func (s *jsonStringifier) jo(obj data.Object) (string, *data.ErrorMsg) {
+	if s.stack[obj] {
+		return "", &data.ErrorMsg{Name: "TypeError", Message: "Converting circular structure to JSON"}
+	}
+	s.stack[obj] = true
+	defer delete(s.stack, obj)
+	stepback := s.indent
+	s.indent += s.gap
+
+	keys := s.propList
+	if keys == nil {
+		for _, key := range obj.OwnPropertyKeys() {
+			pd, ok := obj.GetOwnProperty(key)
+			if !ok || !pd.IsEnumerable() {
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	var parts []string
+	for _, key := range keys {
+		elem, nErr := s.str(key, obj)
+		if nErr != nil {
+			return "", nErr
+		}
+		if elem == nil {
+			continue
+		}
+		sep := ":"
+		if s.gap != "" {
+			sep = ": "
+		}
+		parts = append(parts, quoteJSONString(key)+sep+*elem)
+	}
+	result := s.join(parts, "{", "}")
+	s.indent = stepback
+	return result, nil
+}
+
+// join lays out the already-serialized member strings between the
+// given brackets, applying the current indent/gap if stringify was
+// called with a non-empty space argument.
+func (s *jsonStringifier) join(parts []string, open, close string) string {
+	if len(parts) == 0 {
+		return open + close
+	}
+	if s.gap == "" {
+		return open + strings.Join(parts, ",") + close
+	}
+	sep := ",\n" + s.indent
+	return open + "\n" + s.indent + strings.Join(parts, sep) + "\n" + s.indent[:len(s.indent)-len(s.gap)] + close
+}
+
+// quoteJSONString implements §15.12.3's Quote(value), escaping
+// control characters, '"' and '\\'; other characters (including
+// non-ASCII ones) are passed through unescaped, which is valid JSON.
+func quoteJSONString(str string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range str {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func init() {
+	for _, ni := range builtinJSONNativeImpls {
+		registerNativeImpl(ni)
+	}
+}
@@ -0,0 +1,72 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"CodeCity/server/interpreter/data"
+)
+
+// This file contains code that creates the Number constructor and
+// spec-specified properties on Number.prototype, as well as
+// providing native implementations for many of them.
+
+func (intrp *Interpreter) initBuiltinNumber() {
+	// FIXME: Number should be constructor + conversion function.
+	intrp.mkBuiltin("Number", data.NewObject(nil, intrp.protos.ObjectProto))
+
+	intrp.mkBuiltin("Number.prototype", intrp.protos.NumberProto)
+
+	for _, ni := range builtinNumberNativeImpls {
+		intrp.mkBuiltinFunc(ni.Tag)
+	}
+}
+
+// Latin Letter Sinological Dot ('ꞏ', U+A78F) replaces '.' in names of
+// builtin function implementations.
+
+var builtinNumberNativeImpls = []NativeImpl{
+	{"Number.prototype.toString", builtinNumberꞏprototypeꞏtoString, 1},
+}
+
+// builtinNumberꞏprototypeꞏtoString implements §15.7.4.2:
+// Number.prototype.toString([radix]).  With no radix argument (or
+// radix == 10) it returns the spec-accurate decimal representation
+// computed by data.Number.ToString; otherwise it formats in the
+// given radix (2-36) via data.Number.ToStringRadix.
+func builtinNumberꞏprototypeꞏtoString(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	n, ok := this.(data.Number)
+	if !ok {
+		return intrp.typeError("Number.prototype.toString is not generic"), true
+	}
+	if len(args) == 0 || args[0] == (data.Undefined{}) {
+		return n.ToString(), false
+	}
+	radix := int(args[0].ToNumber())
+	if radix == 10 {
+		return n.ToString(), false
+	}
+	if radix < 2 || radix > 36 {
+		return intrp.rangeError("toString() radix must be between 2 and 36"), true
+	}
+	return n.ToStringRadix(radix), false
+}
+
+func init() {
+	for _, ni := range builtinNumberNativeImpls {
+		registerNativeImpl(ni)
+	}
+}
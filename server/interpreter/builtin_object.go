@@ -47,14 +47,13 @@ var builtinObjectNativeImpls = []NativeImpl{
 	{"Object.create", builtinObjectꞏcreate, 2},
 	{"Object.defineProperty", builtinObjectꞏdefineProperty, 3},
 	{"Object.defineProperties", builtinObjectꞏdefineProperties, 2},
-	// TODO(cpcallen): Finish Implementing §15.2.3 of ES5.1:
-	// {"Object.seal", builtinObjectꞏseal, 1},
-	// {"Object.freeze", builtinObjectꞏfreeze, 1},
-	// {"Object.preventExtensions", builtinObjectꞏpreventExtensions, 1},
-	// {"Object.isSealed", builtinObjectꞏisSealed, 1},
-	// {"Object.isFrozen", builtinObjectꞏisFrozen, 1},
-	// {"Object.isExtensible", builtinObjectꞏisExtensible, 1},
-	// {"Object.keys", builtinObjectꞏkeys, 1},
+	{"Object.seal", builtinObjectꞏseal, 1},
+	{"Object.freeze", builtinObjectꞏfreeze, 1},
+	{"Object.preventExtensions", builtinObjectꞏpreventExtensions, 1},
+	{"Object.isSealed", builtinObjectꞏisSealed, 1},
+	{"Object.isFrozen", builtinObjectꞏisFrozen, 1},
+	{"Object.isExtensible", builtinObjectꞏisExtensible, 1},
+	{"Object.keys", builtinObjectꞏkeys, 1},
 
 	{"Object.prototype.toString", builtinObjectꞏprototypeꞏtoString, 0},
 }
@@ -183,14 +182,144 @@ func builtinObjectꞏdefineProperties(intrp *Interpreter, this data.Value, args
 	return obj, false
 }
 
-// TODO(cpcallen): Finish Implementing §15.2.3 of ES5.1:
-// func builtinObjectꞏseal(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool)
-// func builtinObjectꞏfreeze(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool)
-// func builtinObjectꞏpreventExtensions(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool)
-// func builtinObjectꞏisSealed(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool)
-// func builtinObjectꞏisFrozen(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool)
-// func builtinObjectꞏisExtensible(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool)
-// func builtinObjectꞏkeys(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool)
+// Enforcement of the [[Extensible]] bit and of per-property
+// Writable/Configurable lives in data.Object's DefineOwnProperty,
+// SetProperty and DeleteProperty implementations (see
+// server/interpreter/data/object.go).  SetProperty and DeleteProperty
+// take a strict flag choosing between throwing a TypeError (strict
+// mode) and silently doing nothing (sloppy mode) when mutating a
+// frozen or sealed object; DefineOwnProperty always returns a
+// TypeError for a disallowed change.  The builtins below merely drive
+// those methods.
+
+// builtinObjectꞏseal implements §15.2.3.8: Object.seal(O).  It makes
+// every own property of O non-configurable and prevents further
+// properties from being added, but (unlike freeze) leaves writability
+// alone.
+func builtinObjectꞏseal(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	obj, ok := args[0].(data.Object)
+	if !ok {
+		return intrp.typeError(fmt.Sprintf("Cannot seal %s", args[0].ToString())), true
+	}
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if !ok {
+			continue
+		}
+		pd.Configurable = false
+		if nErr := obj.DefineOwnProperty(key, pd); nErr != nil {
+			return intrp.nativeError(nErr), true
+		}
+	}
+	obj.PreventExtensions()
+	return obj, false
+}
+
+// builtinObjectꞏfreeze implements §15.2.3.9: Object.freeze(O).  It
+// makes every own property of O (all of which are data properties)
+// non-writable and non-configurable and prevents further properties
+// from being added.
+func builtinObjectꞏfreeze(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	obj, ok := args[0].(data.Object)
+	if !ok {
+		return intrp.typeError(fmt.Sprintf("Cannot freeze %s", args[0].ToString())), true
+	}
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if !ok {
+			continue
+		}
+		pd.Writable = false
+		pd.Configurable = false
+		if nErr := obj.DefineOwnProperty(key, pd); nErr != nil {
+			return intrp.nativeError(nErr), true
+		}
+	}
+	obj.PreventExtensions()
+	return obj, false
+}
+
+// builtinObjectꞏpreventExtensions implements §15.2.3.10:
+// Object.preventExtensions(O).
+func builtinObjectꞏpreventExtensions(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	obj, ok := args[0].(data.Object)
+	if !ok {
+		return intrp.typeError(fmt.Sprintf("Cannot prevent extensions on %s", args[0].ToString())), true
+	}
+	obj.PreventExtensions()
+	return obj, false
+}
+
+// builtinObjectꞏisSealed implements §15.2.3.11: Object.isSealed(O).
+func builtinObjectꞏisSealed(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	obj, ok := args[0].(data.Object)
+	if !ok {
+		return intrp.typeError(fmt.Sprintf("Cannot check seal of %s", args[0].ToString())), true
+	}
+	if obj.IsExtensible() {
+		return data.Boolean(false), false
+	}
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if ok && pd.Configurable {
+			return data.Boolean(false), false
+		}
+	}
+	return data.Boolean(true), false
+}
+
+// builtinObjectꞏisFrozen implements §15.2.3.12: Object.isFrozen(O).
+func builtinObjectꞏisFrozen(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	obj, ok := args[0].(data.Object)
+	if !ok {
+		return intrp.typeError(fmt.Sprintf("Cannot check frozen-ness of %s", args[0].ToString())), true
+	}
+	if obj.IsExtensible() {
+		return data.Boolean(false), false
+	}
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if !ok {
+			continue
+		}
+		if pd.Configurable || pd.Writable {
+			return data.Boolean(false), false
+		}
+	}
+	return data.Boolean(true), false
+}
+
+// builtinObjectꞏisExtensible implements §15.2.3.13:
+// Object.isExtensible(O).
+func builtinObjectꞏisExtensible(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	obj, ok := args[0].(data.Object)
+	if !ok {
+		return intrp.typeError(fmt.Sprintf("Cannot check extensibility of %s", args[0].ToString())), true
+	}
+	return data.Boolean(obj.IsExtensible()), false
+}
+
+// builtinObjectꞏkeys implements §15.2.3.14: Object.keys(O), returning
+// an array of O's own enumerable string-keyed property names.
+func builtinObjectꞏkeys(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	obj, ok := args[0].(data.Object)
+	if !ok {
+		return intrp.typeError(fmt.Sprintf("Cannot get keys of %s", args[0].ToString())), true
+	}
+	keys := data.NewArray(nil, intrp.protos.ArrayProto)
+	i := 0
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if !ok || !pd.IsEnumerable() {
+			continue
+		}
+		if nErr := keys.Set(string(data.Number(i).ToString()), data.String(key)); nErr != nil {
+			return intrp.nativeError(nErr), true
+		}
+		i++
+	}
+	return keys, false
+}
 
 /****************************************************************/
 
@@ -0,0 +1,146 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"CodeCity/server/interpreter/data"
+)
+
+// freeze and seal exercise the same DefineOwnProperty/PreventExtensions
+// enforcement that Object.freeze/Object.seal drive (see
+// builtinObjectꞏfreeze/ꞏseal in builtin_object.go), without requiring
+// a full Interpreter to evaluate JS source.
+
+func freeze(t *testing.T, obj data.Object) {
+	t.Helper()
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if !ok {
+			continue
+		}
+		pd.Writable = false
+		pd.Configurable = false
+		if nErr := obj.DefineOwnProperty(key, pd); nErr != nil {
+			t.Fatalf("freeze: DefineOwnProperty(%q) failed: %v", key, nErr)
+		}
+	}
+	obj.PreventExtensions()
+}
+
+func seal(t *testing.T, obj data.Object) {
+	t.Helper()
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if !ok {
+			continue
+		}
+		pd.Configurable = false
+		if nErr := obj.DefineOwnProperty(key, pd); nErr != nil {
+			t.Fatalf("seal: DefineOwnProperty(%q) failed: %v", key, nErr)
+		}
+	}
+	obj.PreventExtensions()
+}
+
+func isFrozen(obj data.Object) bool {
+	if obj.IsExtensible() {
+		return false
+	}
+	for _, key := range obj.OwnPropertyKeys() {
+		pd, ok := obj.GetOwnProperty(key)
+		if !ok {
+			continue
+		}
+		if pd.Configurable || pd.Writable {
+			return false
+		}
+	}
+	return true
+}
+
+// TestFrozenObjectRejectsDefineOwnProperty verifies that a frozen
+// object's properties survive an attempt to redefine them via
+// DefineOwnProperty (the mechanism Object.defineProperty uses): the
+// attempt is rejected with a TypeError and the original value and
+// attributes are left untouched.
+func TestFrozenObjectRejectsDefineOwnProperty(t *testing.T) {
+	obj := data.NewObject(nil, data.ObjectProto)
+	if nErr := obj.Set("x", data.Number(1)); nErr != nil {
+		t.Fatalf("Set failed: %v", nErr)
+	}
+	freeze(t, obj)
+
+	nErr := obj.DefineOwnProperty("x", data.Property{
+		Value: data.Number(2), Writable: true, Enumerable: true, Configurable: true,
+	})
+	if nErr == nil {
+		t.Fatalf("DefineOwnProperty on frozen object's property succeeded; want TypeError")
+	}
+	if nErr.Name != "TypeError" {
+		t.Errorf("DefineOwnProperty error = %v, want TypeError", nErr)
+	}
+
+	pd, ok := obj.GetOwnProperty("x")
+	if !ok {
+		t.Fatalf("property %q disappeared after rejected redefine", "x")
+	}
+	if pd.Value != data.Number(1) || pd.Writable || pd.Configurable {
+		t.Errorf("property %q changed after rejected redefine: got %+v", "x", pd)
+	}
+
+	if nErr := obj.DefineOwnProperty("y", data.Property{Value: data.Number(3)}); nErr == nil {
+		t.Errorf("DefineOwnProperty of new property %q on frozen (non-extensible) object succeeded; want TypeError", "y")
+	}
+}
+
+// TestIsFrozen checks that isFrozen (the logic backing
+// Object.isFrozen) returns true only once every own property is both
+// non-writable and non-configurable and the object itself is
+// non-extensible, and false for lesser states such as merely sealed.
+func TestIsFrozen(t *testing.T) {
+	newObj := func() data.Object {
+		obj := data.NewObject(nil, data.ObjectProto)
+		if nErr := obj.Set("x", data.Number(1)); nErr != nil {
+			t.Fatalf("Set failed: %v", nErr)
+		}
+		return obj
+	}
+
+	if obj := newObj(); isFrozen(obj) {
+		t.Errorf("isFrozen(plain extensible object) = true, want false")
+	}
+
+	sealed := newObj()
+	seal(t, sealed)
+	if isFrozen(sealed) {
+		t.Errorf("isFrozen(sealed-but-writable object) = true, want false")
+	}
+
+	frozen := newObj()
+	freeze(t, frozen)
+	if !isFrozen(frozen) {
+		t.Errorf("isFrozen(frozen object) = false, want true")
+	}
+
+	preventedOnly := newObj()
+	preventedOnly.PreventExtensions()
+	if isFrozen(preventedOnly) {
+		t.Errorf("isFrozen(non-extensible-but-configurable-and-writable object) = true, want false")
+	}
+}
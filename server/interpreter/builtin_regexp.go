@@ -0,0 +1,117 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"strconv"
+
+	"CodeCity/server/interpreter/data"
+)
+
+// This file contains code that creates the RegExp constructor and
+// spec-specified properties on RegExp.prototype, as well as
+// providing native implementations for many of them.
+
+func (intrp *Interpreter) initBuiltinRegExp() {
+	// FIXME: RegExp should be constructor + conversion function.
+	intrp.mkBuiltin("RegExp", data.NewObject(nil, intrp.protos.ObjectProto))
+
+	intrp.mkBuiltin("RegExp.prototype", intrp.protos.RegExpProto)
+
+	for _, ni := range builtinRegExpNativeImpls {
+		intrp.mkBuiltinFunc(ni.Tag)
+	}
+}
+
+// Latin Letter Sinological Dot ('ꞏ', U+A78F) replaces '.' in names of
+// builtin function implementations.
+
+var builtinRegExpNativeImpls = []NativeImpl{
+	{"RegExp.prototype.exec", builtinRegExpꞏprototypeꞏexec, 1},
+	{"RegExp.prototype.test", builtinRegExpꞏprototypeꞏtest, 1},
+}
+
+// builtinRegExpꞏprototypeꞏexec implements §15.10.6.2:
+// RegExp.prototype.exec(string).  For a global RegExp it drives the
+// lastIndex iteration protocol: matching starts at the current
+// lastIndex, which is reset to 0 on failure and advanced past the
+// match on success.
+func builtinRegExpꞏprototypeꞏexec(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	m, nErr := regExpExec(intrp, this, args, "exec")
+	if nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	if m == nil {
+		return data.Null{}, false
+	}
+	arr := data.NewArray(nil, intrp.protos.ArrayProto)
+	for i, g := range m.Groups {
+		if nErr := arr.Set(strconv.Itoa(i), data.String(g)); nErr != nil {
+			return intrp.nativeError(nErr), true
+		}
+	}
+	if nErr := arr.Set("index", data.Number(m.Index)); nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	if nErr := arr.Set("input", data.String(m.Input)); nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	return arr, false
+}
+
+// builtinRegExpꞏprototypeꞏtest implements §15.10.6.3:
+// RegExp.prototype.test(string), which per spec is equivalent to
+// calling exec and checking whether the result is non-null (and so
+// shares exec's lastIndex-updating behaviour for global RegExps).
+func builtinRegExpꞏprototypeꞏtest(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	m, nErr := regExpExec(intrp, this, args, "test")
+	if nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	return data.Boolean(m != nil), false
+}
+
+// regExpExec is the shared matching + lastIndex-update logic behind
+// both RegExp.prototype.exec and RegExp.prototype.test; method is
+// the caller's name (for use in any TypeError message).
+func regExpExec(intrp *Interpreter, this data.Value, args []data.Value, method string) (*data.MatchResult, *data.ErrorMsg) {
+	re, ok := this.(*data.RegExp)
+	if !ok {
+		return nil, &data.ErrorMsg{Name: "TypeError",
+			Message: "RegExp.prototype." + method + " called on incompatible receiver"}
+	}
+	s := string(args[0].ToString())
+	start := 0
+	if re.Global {
+		start = int(re.LastIndex())
+	}
+	m := re.Exec(s, start)
+	if re.Global {
+		if m == nil {
+			re.SetLastIndex(0)
+		} else {
+			re.SetLastIndex(float64(m.Index + len(m.Groups[0])))
+		}
+	}
+	return m, nil
+}
+
+func init() {
+	for _, ni := range builtinRegExpNativeImpls {
+		registerNativeImpl(ni)
+	}
+}
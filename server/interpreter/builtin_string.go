@@ -0,0 +1,290 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"strconv"
+	"strings"
+
+	"CodeCity/server/interpreter/data"
+)
+
+// This file contains code that creates spec-specified properties on
+// String.prototype that deal with regular expressions (§15.5.4.9-11
+// and §15.5.4.14), as well as providing native implementations for
+// them.
+
+func (intrp *Interpreter) initBuiltinString() {
+	intrp.mkBuiltin("String.prototype", intrp.protos.StringProto)
+
+	for _, ni := range builtinStringNativeImpls {
+		intrp.mkBuiltinFunc(ni.Tag)
+	}
+}
+
+// Latin Letter Sinological Dot ('ꞏ', U+A78F) replaces '.' in names of
+// builtin function implementations.
+
+var builtinStringNativeImpls = []NativeImpl{
+	{"String.prototype.match", builtinStringꞏprototypeꞏmatch, 1},
+	{"String.prototype.replace", builtinStringꞏprototypeꞏreplace, 2},
+	{"String.prototype.search", builtinStringꞏprototypeꞏsearch, 1},
+	{"String.prototype.split", builtinStringꞏprototypeꞏsplit, 2},
+}
+
+// toRegExp coerces arg to a *data.RegExp, per the "if not already a
+// RegExp, construct one from ToString(arg)" pattern common to
+// §15.5.4.9-11 and §15.5.4.14: a String (or other non-RegExp) is
+// treated as a pattern with no flags, while undefined becomes the
+// empty pattern.
+func toRegExp(arg data.Value) (*data.RegExp, *data.ErrorMsg) {
+	if re, ok := arg.(*data.RegExp); ok {
+		return re, nil
+	}
+	pattern := ""
+	if arg != nil && arg != (data.Undefined{}) {
+		pattern = string(arg.ToString())
+	}
+	re, err := data.NewRegExp(pattern, "")
+	if err != nil {
+		return nil, &data.ErrorMsg{Name: "SyntaxError", Message: err.Error()}
+	}
+	return re, nil
+}
+
+// builtinStringꞏprototypeꞏmatch implements §15.5.4.10:
+// String.prototype.match(regexp).  For a non-global RegExp it
+// returns the same single-match array as RegExp.prototype.exec; for
+// a global one it returns an array of all matched substrings (with
+// no capture group or index/input detail) and resets lastIndex.
+func builtinStringꞏprototypeꞏmatch(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	s := string(this.ToString())
+	var arg data.Value
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	re, nErr := toRegExp(arg)
+	if nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	if !re.Global {
+		return builtinRegExpꞏprototypeꞏexec(intrp, re, []data.Value{data.String(s)})
+	}
+	arr := data.NewArray(nil, intrp.protos.ArrayProto)
+	i, pos := 0, 0
+	for {
+		m := re.Exec(s, pos)
+		if m == nil {
+			break
+		}
+		if nErr := arr.Set(strconv.Itoa(i), data.String(m.Groups[0])); nErr != nil {
+			return intrp.nativeError(nErr), true
+		}
+		i++
+		pos = m.Index + len(m.Groups[0])
+		if len(m.Groups[0]) == 0 {
+			pos++ // Avoid looping forever on a zero-width match.
+		}
+	}
+	re.SetLastIndex(0)
+	if i == 0 {
+		return data.Null{}, false
+	}
+	return arr, false
+}
+
+// builtinStringꞏprototypeꞏsearch implements §15.5.4.12:
+// String.prototype.search(regexp), returning the index of the first
+// match, or -1 if there is none.  Unlike match/exec, it never
+// consults or updates lastIndex.
+func builtinStringꞏprototypeꞏsearch(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	s := string(this.ToString())
+	var arg data.Value
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	re, nErr := toRegExp(arg)
+	if nErr != nil {
+		return intrp.nativeError(nErr), true
+	}
+	m := re.Exec(s, 0)
+	if m == nil {
+		return data.Number(-1), false
+	}
+	return data.Number(m.Index), false
+}
+
+// builtinStringꞏprototypeꞏsplit implements (a useful subset of)
+// §15.5.4.14: String.prototype.split(separator, limit), splitting on
+// a RegExp separator.  A non-RegExp separator is matched literally
+// via strings.Split rather than being promoted to a one-off RegExp,
+// since that avoids regexp-escaping arbitrary literal text.
+func builtinStringꞏprototypeꞏsplit(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	s := string(this.ToString())
+	arr := data.NewArray(nil, intrp.protos.ArrayProto)
+	if len(args) == 0 || args[0] == (data.Undefined{}) {
+		if nErr := arr.Set("0", data.String(s)); nErr != nil {
+			return intrp.nativeError(nErr), true
+		}
+		return arr, false
+	}
+	limit := -1
+	if len(args) > 1 && args[1] != (data.Undefined{}) {
+		limit = int(args[1].ToNumber())
+	}
+
+	re, ok := args[0].(*data.RegExp)
+	if !ok {
+		sep := string(args[0].ToString())
+		var parts []string
+		if sep == "" {
+			parts = strings.Split(s, "")
+		} else {
+			parts = strings.Split(s, sep)
+		}
+		return setSplitResult(intrp, arr, parts, limit)
+	}
+
+	var parts []string
+	pos, last := 0, 0
+	for pos <= len(s) {
+		m := re.Exec(s, pos)
+		if m == nil {
+			break
+		}
+		if m.Index == last && len(m.Groups[0]) == 0 {
+			pos++
+			continue
+		}
+		parts = append(parts, s[last:m.Index])
+		last = m.Index + len(m.Groups[0])
+		pos = last
+		if len(m.Groups[0]) == 0 {
+			pos++
+		}
+	}
+	parts = append(parts, s[last:])
+	return setSplitResult(intrp, arr, parts, limit)
+}
+
+func setSplitResult(intrp *Interpreter, arr data.Array, parts []string, limit int) (data.Value, bool) {
+	for i, p := range parts {
+		if limit >= 0 && i >= limit {
+			break
+		}
+		if nErr := arr.Set(strconv.Itoa(i), data.String(p)); nErr != nil {
+			return intrp.nativeError(nErr), true
+		}
+	}
+	return arr, false
+}
+
+// builtinStringꞏprototypeꞏreplace implements §15.5.4.11:
+// String.prototype.replace(searchValue, replaceValue), for a RegExp
+// searchValue or (matched literally, first occurrence only) a string
+// one.  replaceValue must be a string (possibly containing
+// "$&"/"$1".."$9" replacement patterns); function replaceValues are
+// not yet supported.
+//
+// TODO(cpcallen): support a function replaceValue, as permitted by
+// the spec.
+func builtinStringꞏprototypeꞏreplace(intrp *Interpreter, this data.Value, args []data.Value) (ret data.Value, throw bool) {
+	s := string(this.ToString())
+	var searchArg, replaceArg data.Value
+	if len(args) > 0 {
+		searchArg = args[0]
+	}
+	if len(args) > 1 {
+		replaceArg = args[1]
+	}
+	replacement := ""
+	if replaceArg != nil {
+		replacement = string(replaceArg.ToString())
+	}
+
+	re, isRegExp := searchArg.(*data.RegExp)
+	if !isRegExp {
+		search := ""
+		if searchArg != nil {
+			search = string(searchArg.ToString())
+		}
+		idx := strings.Index(s, search)
+		if idx < 0 {
+			return data.String(s), false
+		}
+		return data.String(s[:idx] + expandReplacement(replacement, search, nil) + s[idx+len(search):]), false
+	}
+
+	var sb strings.Builder
+	pos, last := 0, 0
+	for {
+		m := re.Exec(s, pos)
+		if m == nil {
+			break
+		}
+		sb.WriteString(s[last:m.Index])
+		sb.WriteString(expandReplacement(replacement, m.Groups[0], m.Groups[1:]))
+		last = m.Index + len(m.Groups[0])
+		pos = last
+		if len(m.Groups[0]) == 0 {
+			pos++
+		}
+		if !re.Global {
+			break
+		}
+	}
+	sb.WriteString(s[last:])
+	if re.Global {
+		re.SetLastIndex(0)
+	}
+	return data.String(sb.String()), false
+}
+
+// expandReplacement substitutes "$$", "$&" and "$1".."$9" in
+// replacement per §15.5.4.11 Table 22.
+func expandReplacement(replacement, match string, groups []string) string {
+	var sb strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		if replacement[i] != '$' || i+1 >= len(replacement) {
+			sb.WriteByte(replacement[i])
+			continue
+		}
+		switch c := replacement[i+1]; {
+		case c == '$':
+			sb.WriteByte('$')
+			i++
+		case c == '&':
+			sb.WriteString(match)
+			i++
+		case c >= '1' && c <= '9':
+			n := int(c - '1')
+			if n < len(groups) {
+				sb.WriteString(groups[n])
+			}
+			i++
+		default:
+			sb.WriteByte('$')
+		}
+	}
+	return sb.String()
+}
+
+func init() {
+	for _, ni := range builtinStringNativeImpls {
+		registerNativeImpl(ni)
+	}
+}
@@ -0,0 +1,81 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import "strconv"
+
+// Array is an Object that additionally tracks the §15.4.5.2 "length"
+// behaviour: setting an own property whose key is an array index via
+// Set updates length to be at least one more than that index.
+// SetProperty and DefineOwnProperty do not update length, and length
+// is not stored as an own "length" property.
+type Array interface {
+	Object
+	// Length returns the array's current length, as maintained by Set.
+	Length() Number
+}
+
+type array struct {
+	*object
+	length int
+}
+
+// NewArray returns a new, empty, extensible Array with the given
+// owner and prototype.
+func NewArray(owner *Owner, proto Value) Array {
+	return &array{object: &object{
+		owner:      owner,
+		proto:      proto,
+		props:      make(map[string]*Property),
+		extensible: true,
+	}}
+}
+
+func (a *array) Length() Number { return Number(a.length) }
+
+// Set overrides object.Set to additionally maintain length, per
+// §15.4.5.1's array index rule.
+func (a *array) Set(key string, value Value) *ErrorMsg {
+	if nErr := a.object.Set(key, value); nErr != nil {
+		return nErr
+	}
+	if idx, ok := arrayIndex(key); ok && idx >= a.length {
+		a.length = idx + 1
+	}
+	return nil
+}
+
+// arrayIndex reports whether key is a valid array index (a
+// non-negative integer, written without leading zeroes), and if so
+// returns its value.
+func arrayIndex(key string) (int, bool) {
+	if key == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(key)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	if strconv.Itoa(n) != key {
+		return 0, false
+	}
+	return n, true
+}
+
+// ArrayProto is the (plain) JavaScript object that is the prototype
+// for all Arrays (i.e. Array.prototype).
+var ArrayProto = NewObject(nil, ObjectProto)
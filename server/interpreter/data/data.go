@@ -0,0 +1,81 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package data defines the representation of JavaScript values used
+// by the interpreter package's builtins: the Value and Object
+// interfaces, the primitive types, and the property-bag machinery
+// (Property, Object.DefineOwnProperty/SetProperty/DeleteProperty)
+// that implements ES5.1's [[Extensible]] and per-property
+// Writable/Configurable/Enumerable attributes.
+package data
+
+// Value is satisfied by every JavaScript value -- primitive or
+// object -- the interpreter can manipulate.
+type Value interface {
+	// Type returns the ES5.1 §8 [[Class]]-ish type name: "undefined",
+	// "boolean", "number", "string" or "object" (Null, like JS typeof
+	// null, reports "object").
+	Type() string
+	// IsPrimitive returns true for Undefined, Null, Boolean, Number
+	// and String, and false for everything else.
+	IsPrimitive() bool
+	// Proto returns the value's prototype, or nil if it has (or can
+	// have) none.
+	Proto() Value
+	// GetProperty implements the §8.12.3 [[Get]] internal method.
+	GetProperty(name string) (Value, *ErrorMsg)
+	// SetProperty implements the §8.12.5 [[Put]] internal method;
+	// strict selects whether a disallowed set throws (true) or is
+	// silently ignored (false), per the calling code's strict mode.
+	SetProperty(name string, value Value, strict bool) *ErrorMsg
+	// HasOwnProperty implements the §8.12.1/[[HasOwnProperty]]
+	// internal method (called HasProperty in some versions of the
+	// spec, but restricted to own properties here).
+	HasOwnProperty(name string) bool
+	// DeleteProperty implements the §8.12.8 [[Delete]] internal
+	// method; strict has the same meaning as for SetProperty.
+	DeleteProperty(name string, strict bool) *ErrorMsg
+	// ToBoolean, ToNumber and ToString implement the corresponding
+	// ES5.1 §9 abstract operations.
+	ToBoolean() Boolean
+	ToNumber() Number
+	ToString() String
+	// ToPrimitive implements the §8.12.8 [[DefaultValue]] internal
+	// method (with implementation-chosen hint).
+	ToPrimitive() Value
+}
+
+// Owner identifies the (server-user) owner of an object for
+// permission-checking purposes elsewhere in the interpreter.  No
+// owner-based behaviour is implemented yet; it exists as a type so
+// that NewObject/NewArray callers (which currently all pass nil) have
+// something concrete to eventually pass.
+type Owner struct{}
+
+// ErrorMsg represents a JavaScript error to be thrown by native
+// (Go-implemented) code -- e.g. as returned by Object.SetProperty, or
+// by ToPropertyDescriptor when given a malformed descriptor object.
+type ErrorMsg struct {
+	Name    string
+	Message string
+}
+
+// Error lets *ErrorMsg satisfy the standard library's error
+// interface, which is convenient when passing it through APIs (e.g.
+// regexp compilation helpers) that expect a Go error.
+func (e *ErrorMsg) Error() string {
+	return e.Name + ": " + e.Message
+}
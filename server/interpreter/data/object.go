@@ -0,0 +1,295 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"fmt"
+	"math"
+)
+
+// Object is satisfied by all JS composite (i.e. non-primitive)
+// values.  Alongside the basic property accessors inherited from
+// Value, it exposes the §8.12 internal methods needed to inspect and
+// mutate a property bag -- [[DefineOwnProperty]], [[Delete]] (via
+// Value.DeleteProperty) -- and the §8.6.2 [[Extensible]] internal
+// property that Object.preventExtensions/isExtensible manipulate.
+type Object interface {
+	Value
+	// OwnPropertyKeys returns the object's own property keys, in
+	// insertion order.
+	OwnPropertyKeys() []string
+	// GetOwnProperty returns the named own property and true, or a
+	// zero Property and false if there is no such own property.
+	GetOwnProperty(key string) (Property, bool)
+	// DefineOwnProperty implements §8.12.9: it creates or redefines
+	// the named own property to have the attributes in pd, applying
+	// the same validity checks (configurability, extensibility) that
+	// Object.defineProperty relies on, and returns a TypeError if the
+	// change is not permitted.
+	DefineOwnProperty(key string, pd Property) *ErrorMsg
+	// Set is an internal convenience, used when constructing objects
+	// (e.g. by JSON.parse or the RegExp exec result) rather than
+	// evaluating JS assignment: it creates an own, writable,
+	// enumerable, configurable data property, or overwrites the value
+	// of an existing own property (leaving its attributes unchanged),
+	// bypassing the [[Extensible]] and attribute-enforcement checks
+	// that SetProperty and DefineOwnProperty apply.
+	Set(key string, value Value) *ErrorMsg
+	// PreventExtensions and IsExtensible implement the §8.6.2
+	// [[Extensible]] internal property manipulated by
+	// Object.preventExtensions/seal/freeze and
+	// Object.isExtensible/isSealed/isFrozen.
+	PreventExtensions()
+	IsExtensible() bool
+}
+
+// object is the standard concrete implementation of Object: a
+// prototype pointer plus an insertion-ordered bag of own properties.
+type object struct {
+	owner      *Owner
+	proto      Value
+	props      map[string]*Property
+	keys       []string
+	extensible bool
+}
+
+// NewObject returns a new, empty, extensible plain object with the
+// given owner and prototype.
+func NewObject(owner *Owner, proto Value) Object {
+	return &object{
+		owner:      owner,
+		proto:      proto,
+		props:      make(map[string]*Property),
+		extensible: true,
+	}
+}
+
+func (o *object) Type() string      { return "object" }
+func (o *object) IsPrimitive() bool { return false }
+func (o *object) Proto() Value      { return o.proto }
+
+func (o *object) OwnPropertyKeys() []string {
+	keys := make([]string, len(o.keys))
+	copy(keys, o.keys)
+	return keys
+}
+
+func (o *object) GetOwnProperty(key string) (Property, bool) {
+	pd, ok := o.props[key]
+	if !ok {
+		return Property{}, false
+	}
+	return *pd, true
+}
+
+func (o *object) HasOwnProperty(name string) bool {
+	_, ok := o.props[name]
+	return ok
+}
+
+// GetProperty implements §8.12.3 [[Get]]: look up the property on
+// the object itself, falling back to the prototype chain.
+func (o *object) GetProperty(name string) (Value, *ErrorMsg) {
+	if pd, ok := o.props[name]; ok {
+		return pd.Value, nil
+	}
+	if o.proto == nil {
+		return Undefined{}, nil
+	}
+	return o.proto.GetProperty(name)
+}
+
+// Set creates an own data property with the default
+// (writable/enumerable/configurable) attributes, or overwrites the
+// value of an existing one without changing its attributes, ignoring
+// [[Extensible]] and Writable; see the Object interface doc comment.
+func (o *object) Set(key string, value Value) *ErrorMsg {
+	if pd, ok := o.props[key]; ok {
+		pd.Value = value
+		return nil
+	}
+	o.props[key] = &Property{Value: value, Writable: true, Enumerable: true, Configurable: true}
+	o.keys = append(o.keys, key)
+	return nil
+}
+
+// SetProperty implements §8.12.5 [[Put]]: ordinary JS assignment,
+// which (unlike Set) respects an existing property's Writable
+// attribute, a non-writable property of the same name further up the
+// prototype chain, and the object's own [[Extensible]] flag -- and,
+// per strict, either throws a TypeError or silently no-ops when the
+// assignment is disallowed.
+func (o *object) SetProperty(name string, value Value, strict bool) *ErrorMsg {
+	if pd, ok := o.props[name]; ok {
+		if !pd.Writable {
+			return cannotSet(name, strict)
+		}
+		pd.Value = value
+		return nil
+	}
+	for p := o.proto; p != nil; {
+		protoObj, ok := p.(Object)
+		if !ok {
+			break
+		}
+		if protoPd, ok := protoObj.GetOwnProperty(name); ok {
+			if !protoPd.Writable {
+				return cannotSet(name, strict)
+			}
+			break
+		}
+		p = protoObj.Proto()
+	}
+	if !o.extensible {
+		return cannotSet(name, strict)
+	}
+	o.props[name] = &Property{Value: value, Writable: true, Enumerable: true, Configurable: true}
+	o.keys = append(o.keys, name)
+	return nil
+}
+
+func cannotSet(name string, strict bool) *ErrorMsg {
+	if !strict {
+		return nil
+	}
+	return &ErrorMsg{"TypeError", fmt.Sprintf("Cannot assign to read only property '%s'", name)}
+}
+
+// DeleteProperty implements §8.12.8 [[Delete]].
+func (o *object) DeleteProperty(name string, strict bool) *ErrorMsg {
+	pd, ok := o.props[name]
+	if !ok {
+		return nil
+	}
+	if !pd.Configurable {
+		if strict {
+			return &ErrorMsg{"TypeError", fmt.Sprintf("Cannot delete property '%s'", name)}
+		}
+		return nil
+	}
+	delete(o.props, name)
+	for i, k := range o.keys {
+		if k == name {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// DefineOwnProperty implements §8.12.9.  Adding a new property
+// requires the object to be extensible; redefining an existing
+// non-configurable property is restricted to the narrow set of
+// changes the spec allows without configurability (in particular,
+// Writable may still be turned from true to false, which is what
+// Object.freeze relies on).
+func (o *object) DefineOwnProperty(key string, pd Property) *ErrorMsg {
+	current, exists := o.props[key]
+	if !exists {
+		if !o.extensible {
+			return &ErrorMsg{"TypeError",
+				fmt.Sprintf("Cannot define property %s, object is not extensible", key)}
+		}
+		stored := pd
+		o.props[key] = &stored
+		o.keys = append(o.keys, key)
+		return nil
+	}
+	if !current.Configurable {
+		if pd.Configurable {
+			return nonConfigurable(key)
+		}
+		if pd.Enumerable != current.Enumerable {
+			return nonConfigurable(key)
+		}
+		if !current.Writable {
+			if pd.Writable {
+				return nonConfigurable(key)
+			}
+			if !sameValue(pd.Value, current.Value) {
+				return nonConfigurable(key)
+			}
+		}
+	}
+	*current = pd
+	return nil
+}
+
+func nonConfigurable(key string) *ErrorMsg {
+	return &ErrorMsg{"TypeError", fmt.Sprintf("Cannot redefine property %s", key)}
+}
+
+// sameValue implements enough of §9.12 SameValue to compare the
+// values of two data properties during DefineOwnProperty: primitives
+// compare by value (with NaN equal to itself, unlike ===), and
+// objects compare by reference.
+func sameValue(a, b Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch av := a.(type) {
+	case Number:
+		bv, ok := b.(Number)
+		return ok && (av == bv || (math.IsNaN(float64(av)) && math.IsNaN(float64(bv))))
+	case String:
+		bv, ok := b.(String)
+		return ok && av == bv
+	case Boolean:
+		bv, ok := b.(Boolean)
+		return ok && av == bv
+	case Null, Undefined:
+		return true
+	default:
+		return a == b
+	}
+}
+
+// PreventExtensions and IsExtensible implement §8.6.2 [[Extensible]].
+func (o *object) PreventExtensions() { o.extensible = false }
+func (o *object) IsExtensible() bool { return o.extensible }
+
+// ToBoolean on an object always returns true.
+func (o *object) ToBoolean() Boolean { return true }
+
+// ToNumber on a plain object returns NaN.
+//
+// BUG(cpcallen): this does not implement the real §9.3 ToNumber (via
+// ToPrimitive with hint Number, calling valueOf/toString); no
+// user-overridable valueOf/toString dispatch exists yet.
+func (o *object) ToNumber() Number { return Number(math.NaN()) }
+
+// ToString on a plain object returns the generic "[object Object]".
+//
+// BUG(cpcallen): this does not implement the real §9.8 ToString (via
+// ToPrimitive with hint String); in particular it ignores any
+// toString method defined on the object or its prototype chain.
+func (o *object) ToString() String { return "[object Object]" }
+
+// ToPrimitive on a plain object just returns the object itself.
+//
+// BUG(cpcallen): this does not implement the real §8.12.8
+// [[DefaultValue]] algorithm (try valueOf then toString, or vice
+// versa for a "string" hint); callers that need an actual primitive
+// should not rely on this.
+func (o *object) ToPrimitive() Value { return o }
+
+// ObjectProto is the (plain) JavaScript object that is the prototype
+// of all other objects (i.e. Object.prototype).
+var ObjectProto = NewObject(nil, nil)
@@ -0,0 +1,268 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"CodeCity/server/interpreter/numfmt"
+)
+
+// Booleans, numbers and strings are represented as immediate data,
+// the same way as in the object package's primitives: do NOT take
+// the address of one.
+
+/********************************************************************/
+
+// Boolean represents a JS boolean value.
+type Boolean bool
+
+var _ Value = Boolean(false)
+
+func (Boolean) Type() string      { return "boolean" }
+func (Boolean) IsPrimitive() bool { return true }
+func (Boolean) Proto() Value      { return BooleanProto }
+
+func (b Boolean) GetProperty(name string) (Value, *ErrorMsg) {
+	return b.Proto().GetProperty(name)
+}
+
+func (Boolean) SetProperty(name string, value Value, strict bool) *ErrorMsg {
+	return nil
+}
+
+func (Boolean) HasOwnProperty(string) bool { return false }
+
+func (Boolean) DeleteProperty(name string, strict bool) *ErrorMsg {
+	return nil
+}
+
+func (b Boolean) ToBoolean() Boolean { return b }
+
+func (b Boolean) ToNumber() Number {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (b Boolean) ToString() String {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func (b Boolean) ToPrimitive() Value { return b }
+
+/********************************************************************/
+
+// Number represents a JS numeric value.
+type Number float64
+
+var _ Value = Number(0)
+
+func (Number) Type() string      { return "number" }
+func (Number) IsPrimitive() bool { return true }
+func (Number) Proto() Value      { return NumberProto }
+
+func (n Number) GetProperty(name string) (Value, *ErrorMsg) {
+	return n.Proto().GetProperty(name)
+}
+
+func (Number) SetProperty(name string, value Value, strict bool) *ErrorMsg {
+	return nil
+}
+
+func (Number) HasOwnProperty(string) bool { return false }
+
+func (Number) DeleteProperty(name string, strict bool) *ErrorMsg {
+	return nil
+}
+
+func (n Number) ToBoolean() Boolean {
+	return Boolean(!(float64(n) == 0 || math.IsNaN(float64(n))))
+}
+
+func (n Number) ToNumber() Number { return n }
+
+// ToString returns the shortest decimal or exponential
+// representation of n that round-trips back to n, per the algorithm
+// in ES5.1 §9.8.1.  The formatting itself lives in the numfmt
+// package, shared with object.Number.ToString, so this and the
+// object package's copy of Number cannot drift apart.
+func (n Number) ToString() String {
+	return String(numfmt.ToString(float64(n)))
+}
+
+// ToStringRadix implements the §15.7.4.2 Number.prototype.toString
+// algorithm for radix values other than 10 (2 <= radix <= 36); for
+// radix == 10 it is equivalent to, and simply defers to, ToString.
+// See the ToString doc comment re: sharing the implementation with
+// object.Number.ToStringRadix via the numfmt package.
+func (n Number) ToStringRadix(radix int) String {
+	return String(numfmt.ToStringRadix(float64(n), radix))
+}
+
+func (n Number) ToPrimitive() Value { return n }
+
+/********************************************************************/
+
+// String represents a JS string value.
+type String string
+
+var _ Value = String("")
+
+func (String) Type() string      { return "string" }
+func (String) IsPrimitive() bool { return true }
+func (String) Proto() Value      { return StringProto }
+
+func (s String) GetProperty(name string) (Value, *ErrorMsg) {
+	if name == "length" {
+		return Number(len(s)), nil
+	}
+	return s.Proto().GetProperty(name)
+}
+
+func (String) SetProperty(name string, value Value, strict bool) *ErrorMsg {
+	return nil
+}
+
+func (s String) HasOwnProperty(name string) bool {
+	return name == "length"
+}
+
+func (s String) DeleteProperty(name string, strict bool) *ErrorMsg {
+	if name != "length" {
+		return nil
+	}
+	if strict {
+		return &ErrorMsg{"TypeError",
+			fmt.Sprintf("Cannot delete property 'length' of %s", s.ToString())}
+	}
+	return nil
+}
+
+func (s String) ToBoolean() Boolean { return len(string(s)) != 0 }
+
+func (s String) ToNumber() Number {
+	str := strings.TrimSpace(string(s))
+	if len(str) == 0 {
+		return 0
+	}
+	if len(str) > 2 {
+		pfx := str[0:2]
+		if pfx == "0x" || pfx == "0X" {
+			n, err := strconv.ParseInt(str[2:], 16, 64)
+			if err != nil {
+				if err.(*strconv.NumError).Err == strconv.ErrSyntax {
+					return Number(math.NaN())
+				}
+				if n > 0 {
+					return Number(math.Inf(+1))
+				}
+				return Number(math.Inf(-1))
+			}
+			return Number(float64(n))
+		}
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		if err.(*strconv.NumError).Err == strconv.ErrSyntax {
+			return Number(math.NaN())
+		}
+	}
+	return Number(n)
+}
+
+func (s String) ToString() String { return s }
+
+func (s String) ToPrimitive() Value { return s }
+
+/********************************************************************/
+
+// Null represents a JS null value.
+type Null struct{}
+
+var _ Value = Null{}
+
+func (Null) Type() string      { return "object" }
+func (Null) IsPrimitive() bool { return true }
+func (Null) Proto() Value      { return nil }
+
+func (Null) GetProperty(name string) (Value, *ErrorMsg) {
+	return nil, &ErrorMsg{"TypeError", fmt.Sprintf("Cannot read property '%s' of null", name)}
+}
+
+func (Null) SetProperty(name string, value Value, strict bool) *ErrorMsg {
+	return &ErrorMsg{"TypeError", fmt.Sprintf("Cannot set property '%s' of null", name)}
+}
+
+func (Null) HasOwnProperty(string) bool { return false }
+
+func (Null) DeleteProperty(name string, strict bool) *ErrorMsg {
+	panic("Null.DeleteProperty() not callable")
+}
+
+func (Null) ToBoolean() Boolean { return false }
+func (Null) ToNumber() Number   { return 0 }
+func (Null) ToString() String   { return "null" }
+func (Null) ToPrimitive() Value { return Null{} }
+
+/********************************************************************/
+
+// Undefined represents a JS undefined value.
+type Undefined struct{}
+
+var _ Value = Undefined{}
+
+func (Undefined) Type() string      { return "undefined" }
+func (Undefined) IsPrimitive() bool { return true }
+func (Undefined) Proto() Value      { return nil }
+
+func (Undefined) GetProperty(name string) (Value, *ErrorMsg) {
+	return nil, &ErrorMsg{"TypeError", fmt.Sprintf("Cannot read property '%s' of undefined", name)}
+}
+
+func (Undefined) SetProperty(name string, value Value, strict bool) *ErrorMsg {
+	return &ErrorMsg{"TypeError", fmt.Sprintf("Cannot set property '%s' of undefined", name)}
+}
+
+func (Undefined) HasOwnProperty(string) bool { return false }
+
+func (Undefined) DeleteProperty(name string, strict bool) *ErrorMsg {
+	panic("Undefined.DeleteProperty() not callable")
+}
+
+func (Undefined) ToBoolean() Boolean { return false }
+func (Undefined) ToNumber() Number   { return Number(math.NaN()) }
+func (Undefined) ToString() String   { return "undefined" }
+func (Undefined) ToPrimitive() Value { return Undefined{} }
+
+/********************************************************************/
+
+// BooleanProto, NumberProto and StringProto are the (plain)
+// JavaScript objects that are the prototypes for all Boolean, Number
+// and String primitives respectively.
+var (
+	BooleanProto = NewObject(nil, ObjectProto)
+	NumberProto  = NewObject(nil, ObjectProto)
+	StringProto  = NewObject(nil, ObjectProto)
+)
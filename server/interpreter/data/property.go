@@ -0,0 +1,98 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+// Property is a §8.10 Property Descriptor for a data property (this
+// interpreter does not yet model accessor properties): it pairs a
+// value with the three boolean attributes that control how the
+// property may subsequently be read, written, enumerated or deleted.
+type Property struct {
+	Value        Value
+	Writable     bool
+	Enumerable   bool
+	Configurable bool
+}
+
+// IsWritable, IsEnumerable and IsConfigurable are convenience
+// accessors for the corresponding attribute, for use in call sites
+// that read more naturally as a method call than a field access.
+func (p Property) IsWritable() bool     { return p.Writable }
+func (p Property) IsEnumerable() bool   { return p.Enumerable }
+func (p Property) IsConfigurable() bool { return p.Configurable }
+
+// ToPropertyDescriptor implements §8.10.5: it reads the "value",
+// "writable", "enumerable" and "configurable" own properties off obj
+// (defaulting any that are absent to their zero value, per the
+// spec's defaults of undefined/false) and returns the corresponding
+// Property.
+func ToPropertyDescriptor(obj Object) (Property, *ErrorMsg) {
+	var pd Property
+	if obj.HasOwnProperty("value") {
+		v, nErr := obj.GetProperty("value")
+		if nErr != nil {
+			return Property{}, nErr
+		}
+		pd.Value = v
+	}
+	if obj.HasOwnProperty("writable") {
+		v, nErr := obj.GetProperty("writable")
+		if nErr != nil {
+			return Property{}, nErr
+		}
+		pd.Writable = bool(v.ToBoolean())
+	}
+	if obj.HasOwnProperty("enumerable") {
+		v, nErr := obj.GetProperty("enumerable")
+		if nErr != nil {
+			return Property{}, nErr
+		}
+		pd.Enumerable = bool(v.ToBoolean())
+	}
+	if obj.HasOwnProperty("configurable") {
+		v, nErr := obj.GetProperty("configurable")
+		if nErr != nil {
+			return Property{}, nErr
+		}
+		pd.Configurable = bool(v.ToBoolean())
+	}
+	return pd, nil
+}
+
+// FromPropertyDescriptor implements §8.10.4: it builds a fresh plain
+// object with own "value", "writable", "enumerable" and
+// "configurable" properties reflecting pd.
+func FromPropertyDescriptor(pd Property, owner *Owner, proto Value) (Value, *ErrorMsg) {
+	obj := NewObject(owner, proto)
+	value := pd.Value
+	if value == nil {
+		value = Undefined{}
+	}
+	for _, kv := range []struct {
+		key string
+		val Value
+	}{
+		{"value", value},
+		{"writable", Boolean(pd.Writable)},
+		{"enumerable", Boolean(pd.Enumerable)},
+		{"configurable", Boolean(pd.Configurable)},
+	} {
+		if nErr := obj.Set(kv.key, kv.val); nErr != nil {
+			return nil, nErr
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,138 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"fmt"
+	"math"
+
+	"CodeCity/server/interpreter/object"
+)
+
+// RegExp represents a JS regular expression value: the interpreter
+// package's builtin_regexp.go and builtin_string.go drive it to
+// implement RegExp.prototype.exec/test and the RegExp-accepting
+// String.prototype methods.
+//
+// The pattern-translation and matching engine is not reimplemented
+// here: RegExp embeds an *object.RegExp (see object/regexp.go),
+// inheriting its Source/Global/IgnoreCase/Multiline fields and its
+// LastIndex/SetLastIndex/Exec methods unchanged, so the two packages'
+// Value representations share exactly one regex engine rather than
+// risking two copies drifting apart. What this type adds is the glue
+// needed to satisfy data.Value, whose GetProperty/SetProperty/etc.
+// signatures -- strict bool included -- differ from object.Value's,
+// and so must be (and are, below) overridden rather than promoted.
+type RegExp struct {
+	*object.RegExp
+}
+
+var _ Value = (*RegExp)(nil)
+
+// NewRegExp compiles the given JS regular expression source and flag
+// string into a *RegExp; see object.NewRegExp for the compilation and
+// syntax-translation rules.
+func NewRegExp(source, flags string) (*RegExp, error) {
+	r, err := object.NewRegExp(source, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &RegExp{r}, nil
+}
+
+// FromObjectRegExp wraps an existing *object.RegExp -- e.g. one
+// produced by object.NewFromRaw when parsing a /pattern/flags source
+// literal -- as a *data.RegExp, the type the interpreter's builtins
+// operate on. This is the bridge between the two packages' otherwise
+// separate Value representations for RegExp.
+func FromObjectRegExp(r *object.RegExp) *RegExp {
+	return &RegExp{r}
+}
+
+// MatchResult is an alias for object.MatchResult: the shape of a
+// match result doesn't depend on which package's RegExp produced it,
+// so it isn't duplicated here. (*RegExp).Exec, promoted from the
+// embedded *object.RegExp, already returns this type.
+type MatchResult = object.MatchResult
+
+func (*RegExp) Type() string      { return "object" }
+func (*RegExp) IsPrimitive() bool { return false }
+func (*RegExp) Proto() Value      { return RegExpProto }
+
+func (re *RegExp) GetProperty(name string) (Value, *ErrorMsg) {
+	switch name {
+	case "source":
+		return String(re.Source), nil
+	case "global":
+		return Boolean(re.Global), nil
+	case "ignoreCase":
+		return Boolean(re.IgnoreCase), nil
+	case "multiline":
+		return Boolean(re.Multiline), nil
+	case "lastIndex":
+		return Number(re.LastIndex()), nil
+	default:
+		return re.Proto().GetProperty(name)
+	}
+}
+
+// SetProperty allows setting "lastIndex" (the only writable own
+// property of a RegExp per §15.10.7); attempts to set any other own
+// property throw in strict mode and are otherwise silently ignored,
+// matching their non-writable attribute.
+func (re *RegExp) SetProperty(name string, value Value, strict bool) *ErrorMsg {
+	if name == "lastIndex" {
+		re.SetLastIndex(float64(value.ToNumber()))
+		return nil
+	}
+	if strict && re.HasOwnProperty(name) {
+		return &ErrorMsg{"TypeError", fmt.Sprintf("Cannot assign to read only property '%s'", name)}
+	}
+	return nil
+}
+
+func (re *RegExp) HasOwnProperty(name string) bool {
+	switch name {
+	case "source", "global", "ignoreCase", "multiline", "lastIndex":
+		return true
+	default:
+		return false
+	}
+}
+
+// DeleteProperty always fails (throwing in strict mode) for the
+// non-configurable own properties of a RegExp, and otherwise succeeds
+// without effect.
+func (re *RegExp) DeleteProperty(name string, strict bool) *ErrorMsg {
+	if re.HasOwnProperty(name) && strict {
+		return &ErrorMsg{"TypeError",
+			fmt.Sprintf("Cannot delete property '%s' of %s", name, re.ToString())}
+	}
+	return nil
+}
+
+func (*RegExp) ToBoolean() Boolean { return true }
+func (*RegExp) ToNumber() Number   { return Number(math.NaN()) }
+
+// ToString returns the literal form of the RegExp, e.g. "/foo/gi".
+func (re *RegExp) ToString() String { return String(re.RegExp.ToString()) }
+
+func (re *RegExp) ToPrimitive() Value { return re.ToString() }
+
+// RegExpProto is the (plain) JavaScript object that is the prototype
+// for all RegExp values (i.e. RegExp.prototype).
+var RegExpProto = NewObject(nil, ObjectProto)
@@ -0,0 +1,160 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package numfmt implements the ES5.1 §9.8.1 and §15.7.4.2 algorithms
+// for converting a float64 to the decimal or radix-N string a JS
+// Number's ToString/toString(radix) must produce.  It exists so that
+// the object and data packages' otherwise-identical Number types
+// share exactly one implementation, rather than risking the two
+// copies drifting apart (or one silently missing a feature the other
+// has, as happened with ToStringRadix).
+package numfmt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ToString implements §9.8.1 ToString Applied to the Number Type: the
+// shortest decimal or exponential representation of f that
+// round-trips back to f, with "NaN"/"Infinity"/"-Infinity" for the
+// corresponding non-finite values.
+func ToString(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case f == 0:
+		// §9.8.1 step 5: both +0 and -0 render as "0".
+		return "0"
+	case f < 0:
+		return "-" + ToString(-f)
+	case math.IsInf(f, 0):
+		return "Infinity"
+	}
+	digits, n := shortestDigits(f)
+	return formatDigits(digits, n)
+}
+
+// shortestDigits returns the shortest decimal digit string s and
+// exponent n such that, per §9.8.1, 10^(n-k) * s == f (where k ==
+// len(s)), for finite, positive, non-zero f.  It relies on Go's
+// strconv shortest-round-trip formatter, which implements the same
+// "shortest string that reads back to f" guarantee required by the
+// spec algorithm.
+func shortestDigits(f float64) (digits string, n int) {
+	buf := strconv.AppendFloat(nil, f, 'e', -1, 64)
+	s := string(buf)
+	eIdx := strings.IndexByte(s, 'e')
+	mantissa := strings.Replace(s[:eIdx], ".", "", 1)
+	exp, err := strconv.Atoi(s[eIdx+1:])
+	if err != nil {
+		panic(err) // Can't happen: strconv always emits a valid exponent.
+	}
+	return mantissa, exp + 1
+}
+
+// formatDigits applies the §9.8.1 steps 6-9 dispatch between fixed
+// and exponential notation to the given digit string and exponent
+// (as returned by shortestDigits).
+func formatDigits(digits string, n int) string {
+	k := len(digits)
+	switch {
+	case k <= n && n <= 21:
+		// Integer, possibly padded with trailing zeroes.
+		return digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		// Digits split by a decimal point within the digit string.
+		return digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		// Fraction with leading zeroes after the decimal point.
+		return "0." + strings.Repeat("0", -n) + digits
+	default:
+		// Exponential notation: d.ddde±nn.
+		var sb strings.Builder
+		sb.WriteByte(digits[0])
+		if k > 1 {
+			sb.WriteByte('.')
+			sb.WriteString(digits[1:])
+		}
+		e := n - 1
+		if e >= 0 {
+			sb.WriteString("e+")
+		} else {
+			sb.WriteString("e-")
+			e = -e
+		}
+		sb.WriteString(strconv.Itoa(e))
+		return sb.String()
+	}
+}
+
+// radixDigits are the digits used by ToStringRadix, in order, for
+// radixes up to the maximum of 36 allowed by §15.7.4.2.
+const radixDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// maxRadixFracDigits bounds the number of fractional digits
+// ToStringRadix will generate for a non-terminating fractional part;
+// the ES5.1 spec leaves the precision of non-decimal fractional
+// conversion implementation-defined, so this is chosen generously
+// (it comfortably exceeds the ~52 bits of precision a float64 can
+// represent) rather than derived from the spec.
+const maxRadixFracDigits = 1100
+
+// ToStringRadix implements the §15.7.4.2 Number.prototype.toString
+// algorithm for radix values other than 10 (2 <= radix <= 36); for
+// radix == 10 it is equivalent to, and simply defers to, ToString.
+func ToStringRadix(f float64, radix int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case f < 0:
+		return "-" + ToStringRadix(-f, radix)
+	case math.IsInf(f, 0):
+		return "Infinity"
+	case radix == 10:
+		return ToString(f)
+	}
+	r := float64(radix)
+	intPart, fracPart := math.Modf(f)
+
+	var ip strings.Builder
+	if intPart == 0 {
+		ip.WriteByte('0')
+	} else {
+		var digits []byte
+		for intPart > 0 {
+			d := math.Mod(intPart, r)
+			digits = append(digits, radixDigits[int(d)])
+			intPart = math.Trunc(intPart / r)
+		}
+		for i := len(digits) - 1; i >= 0; i-- {
+			ip.WriteByte(digits[i])
+		}
+	}
+	if fracPart == 0 {
+		return ip.String()
+	}
+
+	var fp strings.Builder
+	for i := 0; i < maxRadixFracDigits && fracPart != 0; i++ {
+		fracPart *= r
+		d, frac := math.Modf(fracPart)
+		fp.WriteByte(radixDigits[int(d)])
+		fracPart = frac
+	}
+	return ip.String() + "." + fp.String()
+}
@@ -0,0 +1,75 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object_test
+
+import (
+	"math"
+	"testing"
+
+	"CodeCity/server/interpreter/object"
+)
+
+func TestNumberToString(t *testing.T) {
+	tests := []struct {
+		name string
+		n    object.Number
+		want string
+	}{
+		{"positive zero", object.Number(0), "0"},
+		{"negative zero", object.Number(math.Copysign(0, -1)), "0"},
+		{"smallest subnormal", object.Number(4.9406564584124654e-324), "5e-324"},
+		{"smallest normal", object.Number(2.2250738585072014e-308), "2.2250738585072014e-308"},
+		{"just below 1e21 boundary", object.Number(1e20), "100000000000000000000"},
+		{"1e21 boundary", object.Number(1e21), "1e+21"},
+		{"just above 1e-6 fixed cutoff", object.Number(1.5e-6), "0.0000015"},
+		{"1e-6 boundary", object.Number(1e-6), "0.000001"},
+		{"just below 1e-6 boundary", object.Number(9e-7), "9e-7"},
+		{"NaN", object.Number(math.NaN()), "NaN"},
+		{"+Infinity", object.Number(math.Inf(1)), "Infinity"},
+		{"-Infinity", object.Number(math.Inf(-1)), "-Infinity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.n.ToString()); got != tt.want {
+				t.Errorf("object.Number(%v).ToString() = %q, want %q", float64(tt.n), got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNumberToStringRoundTrip checks that converting a number to a
+// string and parsing that string back (via object.String.ToNumber)
+// recovers a value whose own ToString is identical to the original
+// string, i.e. String(ToNumber(String(x))) == String(x), for a
+// sample of values spanning the fixed/exponential notation boundaries
+// exercised above.
+func TestNumberToStringRoundTrip(t *testing.T) {
+	values := []float64{
+		0, math.Copysign(0, -1), 1, -1, 0.5, 123456789,
+		4.9406564584124654e-324, 2.2250738585072014e-308,
+		1e21, 1e-6, 9e-7, 1.5e-6, 999999999999999900000,
+		math.MaxFloat64, 3.141592653589793,
+	}
+	for _, f := range values {
+		n := object.Number(f)
+		s := n.ToString()
+		roundTripped := object.String(s).ToNumber().ToString()
+		if roundTripped != s {
+			t.Errorf("round-trip failed for %v: String(ToNumber(%q)) = %q, want %q", f, s, roundTripped, s)
+		}
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"strings"
 	"unicode"
 	"unicode/utf16"
+
+	"CodeCity/server/interpreter/numfmt"
 )
 
 // Booleans, numbers and strings are represented as immediate data -
@@ -33,11 +35,29 @@ import (
 //
 // tl;dr: do NOT take the address of a primitive.
 
+// NewFromRawOptions controls optional, non-default parsing behaviour
+// for NewFromRawWithOptions.
+type NewFromRawOptions struct {
+	// Strict selects ES5.1 strict mode parsing rules; in particular
+	// legacy octal literals like "013" (Annex B.1.1) are only
+	// recognized in sloppy mode.  In strict mode such a literal is not
+	// rejected but read as decimal ("013" gives 13).
+	Strict bool
+}
+
 // NewFromRaw takes a raw JavaScript literal (as a string as it
 // appears in the source code, and as found in an ast.Literal.Raw
 // property) and returns a primitive Value object representing the
-// value of that literal.
+// value of that literal.  It is equivalent to
+// NewFromRawWithOptions(raw, NewFromRawOptions{Strict: true}).
 func NewFromRaw(raw string) Value {
+	return NewFromRawWithOptions(raw, NewFromRawOptions{Strict: true})
+}
+
+// NewFromRawWithOptions is like NewFromRaw but additionally accepts
+// options controlling parsing of literal forms (e.g. legacy octal)
+// whose validity depends on strict vs. sloppy mode.
+func NewFromRawWithOptions(raw string, opts NewFromRawOptions) Value {
 	if raw == "true" {
 		return Boolean(true)
 	} else if raw == "false" {
@@ -53,22 +73,107 @@ func NewFromRaw(raw string) Value {
 		}
 		return String(s)
 	} else if raw[0] == '\'' {
-		// BUG(cpcallen): single-quoted string literals not implemented.
-		panic(fmt.Errorf("Single-quoted string literals not implemented"))
+		s, err := unquoteSingleQuoted(raw)
+		if err != nil {
+			panic(err)
+		}
+		return String(s)
 	} else if unicode.IsDigit(rune(raw[0])) {
+		if len(raw) > 2 && raw[0] == '0' && (raw[1] == 'o' || raw[1] == 'O') {
+			n, err := strconv.ParseInt(raw[2:], 8, 64)
+			if err != nil {
+				panic(err)
+			}
+			return Number(n)
+		} else if len(raw) > 2 && raw[0] == '0' && (raw[1] == 'b' || raw[1] == 'B') {
+			n, err := strconv.ParseInt(raw[2:], 2, 64)
+			if err != nil {
+				panic(err)
+			}
+			return Number(n)
+		} else if !opts.Strict && len(raw) > 1 && raw[0] == '0' && isLegacyOctal(raw[1:]) {
+			n, err := strconv.ParseInt(raw[1:], 8, 64)
+			if err != nil {
+				panic(err)
+			}
+			return Number(n)
+		}
 		// BUG(cpcallen): numeric literals probably not handled
 		// completely in accordance with ES5.1 spec; it is implemented
 		// using String.ToNumber which may be unduly tolerant and
 		// handle certain edge cases differently.
 		return String(raw).ToNumber()
 	} else if raw[0] == '/' {
-		// BUG(cpcallen): regular expresion literals not implemented.
-		panic(fmt.Errorf("Regular Expression literals not implemented"))
+		idx := strings.LastIndexByte(raw, '/')
+		if idx <= 0 {
+			panic(fmt.Errorf("Malformed regular expression literal %v", raw))
+		}
+		re, err := NewRegExp(raw[1:idx], raw[idx+1:])
+		if err != nil {
+			panic(err)
+		}
+		return re
 	} else {
 		panic(fmt.Errorf("Unrecognized raw literal %v", raw))
 	}
 }
 
+// unquoteSingleQuoted converts a single-quoted JS string literal (as
+// found in raw source) to its string value.  JS allows the same
+// escape sequences in single- and double-quoted strings, differing
+// only in which quote character must be escaped; we exploit this by
+// re-escaping the body as a double-quoted Go string literal and
+// reusing strconv.Unquote.
+func unquoteSingleQuoted(raw string) (string, error) {
+	if len(raw) < 2 || raw[0] != '\'' || raw[len(raw)-1] != '\'' {
+		return "", fmt.Errorf("Malformed single-quoted string literal %v", raw)
+	}
+	body := raw[1 : len(raw)-1]
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body) && body[i+1] == '\'':
+			// \' is the only escape that needs rewriting: single-quoted
+			// strings don't need to escape ' and strconv.Unquote (which
+			// is only told about double-quoted strings) doesn't
+			// recognize \' as an escape.
+			sb.WriteByte('\'')
+			i++
+		case body[i] == '\\' && i+1 < len(body):
+			// Every other escape (\", \\, \n, \uXXXX, ...) is identical
+			// between single- and double-quoted strings, so pass it
+			// through untouched for strconv.Unquote to interpret.
+			sb.WriteByte('\\')
+			sb.WriteByte(body[i+1])
+			i++
+		case body[i] == '"':
+			sb.WriteString(`\"`)
+		default:
+			sb.WriteByte(body[i])
+		}
+	}
+	sb.WriteByte('"')
+	return strconv.Unquote(sb.String())
+}
+
+// isLegacyOctal reports whether s (the digits of a numeric literal
+// following a leading "0") consists entirely of octal digits, as
+// required for it to be treated as an Annex B.1.1 legacy octal
+// literal rather than a decimal one (e.g. "013" is octal 11, but
+// "08" is decimal 8).
+func isLegacyOctal(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '7' {
+			return false
+		}
+	}
+	return true
+}
+
 /********************************************************************/
 
 // Boolean represents a JS boolean value.
@@ -194,24 +299,24 @@ func (n Number) ToNumber() Number {
 }
 
 // ToString on a number returns "Infinity" for +Inf, "-Infinity" for
-// -Inf, "NaN" for NaN, and a decimal or exponential representation
-// for regular numeric values.
+// -Inf, "NaN" for NaN, and otherwise the shortest decimal or
+// exponential representation that round-trips back to n, per the
+// algorithm in ES5.1 §9.8.1 (ToString Applied to the Number Type).
 //
-// BUG(cpcallen): This implementation is probably not strictly
-// compatible with the ES5.1 spec.  In particular, transtion from
-// decimal to exponential representation is not guaranteed to be
-// compliant.
-//
-// FIXME: Should we return "-0" for negative zero?  Do we?
+// The actual formatting is implemented once, in the numfmt package,
+// and shared with data.Number.ToString so the two copies of Number
+// cannot drift apart.
 func (n Number) ToString() String {
-	switch float64(n) {
-	case math.Inf(+1):
-		return "Infinity"
-	case math.Inf(-1):
-		return "-Infinity"
-	default:
-		return String(fmt.Sprintf("%g", n))
-	}
+	return String(numfmt.ToString(float64(n)))
+}
+
+// ToStringRadix implements the §15.7.4.2 Number.prototype.toString
+// algorithm for radix values other than 10 (2 <= radix <= 36); for
+// radix == 10 it is equivalent to, and simply defers to, ToString.
+// See the ToString doc comment re: sharing the implementation with
+// data.Number.ToStringRadix via the numfmt package.
+func (n Number) ToStringRadix(radix int) String {
+	return String(numfmt.ToStringRadix(float64(n), radix))
 }
 
 // ToPrimitive on a primitive just returns itself.
@@ -0,0 +1,282 @@
+/* Copyright 2017 Google Inc.
+ * https://github.com/NeilFraser/CodeCity
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package object
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// RegExp represents a JS regular expression value (the result of a
+// /pattern/flags literal, as parsed by NewFromRaw, or of calling the
+// RegExp constructor).  Unlike Boolean/Number/String it is not a
+// primitive -- per §15.10, a RegExp is an object -- but it is defined
+// here alongside them since it, too, wraps a small immutable Go value
+// (a compiled pattern) rather than being built out of ordinary
+// properties.
+type RegExp struct {
+	// Source is the original, untranslated JS pattern text (i.e. the
+	// part between the slashes of a /pattern/flags literal).
+	Source string
+	// Global, IgnoreCase and Multiline correspond to the 'g', 'i' and
+	// 'm' flags respectively.
+	Global     bool
+	IgnoreCase bool
+	Multiline  bool
+	// lastIndex is the value of the writable "lastIndex" own
+	// property used by the exec/test "g"-flag iteration protocol.
+	lastIndex float64
+	// re is Source translated to, and compiled as, Go regexp syntax.
+	re *regexp.Regexp
+}
+
+// RegExp must satisfy Value.
+var _ Value = (*RegExp)(nil)
+
+// NewRegExp compiles the given JS regular expression source and flag
+// string (as found between and after the slashes of a
+// /pattern/flags literal) into a *RegExp, translating JS regexp
+// syntax to the syntax accepted by Go's regexp package.
+func NewRegExp(source, flags string) (*RegExp, error) {
+	r := &RegExp{Source: source}
+	for _, f := range flags {
+		switch f {
+		case 'g':
+			r.Global = true
+		case 'i':
+			r.IgnoreCase = true
+		case 'm':
+			r.Multiline = true
+		default:
+			return nil, fmt.Errorf("Invalid regular expression flag %q", f)
+		}
+	}
+	goPattern, err := translateRegExpSyntax(source)
+	if err != nil {
+		return nil, err
+	}
+	var prefix string
+	if r.IgnoreCase {
+		prefix += "i"
+	}
+	if r.Multiline {
+		prefix += "m"
+	}
+	if prefix != "" {
+		goPattern = "(?" + prefix + ")" + goPattern
+	}
+	re, err := regexp.Compile(goPattern)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid regular expression /%s/: %v", source, err)
+	}
+	r.re = re
+	return r, nil
+}
+
+// translateRegExpSyntax converts a JS regular expression pattern to
+// the closest equivalent accepted by Go's regexp/syntax (which is
+// otherwise very similar to JS's, as both are Perl-derived).  The
+// character classes \d, \w and \s (and their uppercase negations)
+// are already compatible and need no translation.  Backreferences
+// (\1, \2, ...), which Go's RE2 engine cannot support, are rejected
+// rather than silently mishandled.
+//
+// TODO(cpcallen): translate other JS-only constructs (e.g.
+// lookahead) once a translation or emulation strategy is decided on.
+func translateRegExpSyntax(pattern string) (string, error) {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			if c := pattern[i+1]; c >= '1' && c <= '9' {
+				return "", fmt.Errorf("Backreferences are not supported in regular expressions")
+			}
+			i++
+		}
+	}
+	return pattern, nil
+}
+
+// Type always returns "object" for RegExps.
+func (*RegExp) Type() string {
+	return "object"
+}
+
+// IsPrimitive always returns false for RegExps.
+func (*RegExp) IsPrimitive() bool {
+	return false
+}
+
+// Proto returns RegExpProto for all RegExps.
+func (*RegExp) Proto() Value {
+	return RegExpProto
+}
+
+// GetProperty implements the own properties specified by §15.10.7
+// ("source", "global", "ignoreCase", "multiline" and "lastIndex"),
+// and otherwise passes through to RegExpProto.
+func (r *RegExp) GetProperty(name string) (Value, *ErrorMsg) {
+	switch name {
+	case "source":
+		return String(r.Source), nil
+	case "global":
+		return Boolean(r.Global), nil
+	case "ignoreCase":
+		return Boolean(r.IgnoreCase), nil
+	case "multiline":
+		return Boolean(r.Multiline), nil
+	case "lastIndex":
+		return Number(r.lastIndex), nil
+	default:
+		return r.Proto().GetProperty(name)
+	}
+}
+
+// SetProperty allows setting "lastIndex" (the only writable own
+// property of a RegExp per §15.10.7); all other property sets are
+// silently ignored.
+func (r *RegExp) SetProperty(name string, value Value) *ErrorMsg {
+	if name == "lastIndex" {
+		r.lastIndex = float64(value.ToNumber())
+	}
+	return nil
+}
+
+// LastIndex returns the current value of the "lastIndex" own
+// property, used by callers (e.g. the interpreter's exec/test
+// builtins) driving the "g"-flag iteration protocol of §15.10.6.2.
+func (r *RegExp) LastIndex() float64 {
+	return r.lastIndex
+}
+
+// SetLastIndex sets the "lastIndex" own property; equivalent to
+// SetProperty("lastIndex", Number(n)).
+func (r *RegExp) SetLastIndex(n float64) {
+	r.lastIndex = n
+}
+
+func (*RegExp) propNames() []string {
+	return []string{"source", "global", "ignoreCase", "multiline", "lastIndex"}
+}
+
+// HasOwnProperty returns true for the five own properties listed in
+// propNames, and false otherwise.
+func (r *RegExp) HasOwnProperty(name string) bool {
+	for _, n := range r.propNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteProperty always fails (returns a TypeError) for the
+// non-configurable own properties of a RegExp, and otherwise
+// succeeds without effect.
+func (r *RegExp) DeleteProperty(name string) *ErrorMsg {
+	if r.HasOwnProperty(name) {
+		return &ErrorMsg{"TypeError",
+			fmt.Sprintf("Cannot delete property '%s' of %s", name, r.ToString())}
+	}
+	return nil
+}
+
+// ToBoolean on a RegExp always returns true, as for all objects.
+func (*RegExp) ToBoolean() Boolean {
+	return true
+}
+
+// ToNumber on a RegExp always returns NaN, as for all objects with
+// no overridden [[DefaultValue]].
+func (*RegExp) ToNumber() Number {
+	return Number(math.NaN())
+}
+
+// ToString returns the literal form of the RegExp, e.g. "/foo/gi".
+func (r *RegExp) ToString() String {
+	var flags strings.Builder
+	if r.Global {
+		flags.WriteByte('g')
+	}
+	if r.IgnoreCase {
+		flags.WriteByte('i')
+	}
+	if r.Multiline {
+		flags.WriteByte('m')
+	}
+	return String("/" + r.Source + "/" + flags.String())
+}
+
+// ToPrimitive on a RegExp returns its ToString() representation,
+// per the default [[DefaultValue]] algorithm for objects.
+func (r *RegExp) ToPrimitive() Value {
+	return r.ToString()
+}
+
+// MatchResult holds the result of a successful match: the index (in
+// s) at which the match was found, the full subject string, and the
+// matched text of the whole match (Groups[0]) and of each capturing
+// group (Groups[1:]; an empty string for a non-participating group).
+//
+// BUG(cpcallen): Index and the offsets used to find it are byte
+// offsets into the (UTF-8) Go string, not the UTF-16 code unit
+// offsets required by §15.10.6.2; these coincide only for strings
+// with no non-ASCII characters before the match.
+type MatchResult struct {
+	Index  int
+	Input  string
+	Groups []string
+}
+
+// Exec runs the RegExp against s, returning the first match whose
+// start is at or after byte offset start, implementing the core
+// matching behaviour behind §15.10.6.2 RegExp.prototype.exec (the
+// interpreter builtin is responsible for the surrounding lastIndex
+// iteration protocol).  It returns nil if there is no such match.
+//
+// Matching is always performed against the whole of s, rather than
+// against s[start:], so that '^' (and, with the 'm' flag, line-start
+// '^') only ever anchors to the true start of s or of a line within
+// it, not to the search offset.
+func (r *RegExp) Exec(s string, start int) *MatchResult {
+	if start < 0 || start > len(s) {
+		return nil
+	}
+	// BUG(cpcallen): this rescans s from the beginning on every call,
+	// which is O(n) per match rather than O(1); fine for the small
+	// strings this interpreter typically deals with, but not for
+	// tight loops over long strings.
+	for _, loc := range r.re.FindAllStringSubmatchIndex(s, -1) {
+		if loc[0] < start {
+			continue
+		}
+		groups := make([]string, len(loc)/2)
+		for i := range groups {
+			if loc[2*i] < 0 {
+				continue // Non-participating group.
+			}
+			groups[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+		return &MatchResult{Index: loc[0], Input: s, Groups: groups}
+	}
+	return nil
+}
+
+// RegExpProto is the (plain) JavaScript object that is the
+// prototype for all RegExp values.  (It would usually be accessed in
+// JavaScript as RegExp.prototype.)
+var RegExpProto = New(nil, ObjectProto)